@@ -0,0 +1,391 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// subscription is one `/subscribe` rule for a chat: it matches translations
+// by region and/or tournament and reminds lead before OriginalTime.
+type subscription struct {
+	Region     string        `json:"region,omitempty"`
+	Tournament string        `json:"tournament,omitempty"`
+	Lead       time.Duration `json:"lead"`
+}
+
+// chatState is the per-chat data persisted under --state-dir.
+type chatState struct {
+	TZ            string         `json:"tz,omitempty"`
+	Subscriptions []subscription `json:"subscriptions,omitempty"`
+	notified      map[string]bool
+}
+
+// botStore is a JSON-file-backed store of chatState keyed by Telegram chat
+// ID, guarded by a mutex since the update loop and the reminder loop touch
+// it concurrently.
+type botStore struct {
+	path string
+
+	mu     sync.Mutex
+	states map[int64]*chatState
+}
+
+func loadBotStore(stateDir string) (*botStore, error) {
+	store := &botStore{path: filepath.Join(stateDir, "subscriptions.json"), states: make(map[int64]*chatState)}
+
+	data, err := os.ReadFile(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.states); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *botStore) save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
+
+func (s *botStore) stateLocked(chatID int64) *chatState {
+	state, ok := s.states[chatID]
+	if !ok {
+		state = &chatState{}
+		s.states[chatID] = state
+	}
+	return state
+}
+
+func (s *botStore) snapshot(chatID int64) chatState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.stateLocked(chatID)
+}
+
+func (s *botStore) chatIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]int64, 0, len(s.states))
+	for id := range s.states {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *botStore) setTZ(chatID int64, tz string) error {
+	s.mu.Lock()
+	s.stateLocked(chatID).TZ = tz
+	s.mu.Unlock()
+	return s.save()
+}
+
+func (s *botStore) addSubscription(chatID int64, sub subscription) error {
+	s.mu.Lock()
+	state := s.stateLocked(chatID)
+	state.Subscriptions = append(state.Subscriptions, sub)
+	s.mu.Unlock()
+	return s.save()
+}
+
+// markNotified reports whether uid had not already been reminded for
+// chatID, marking it as notified as a side effect. It is not persisted, so a
+// bot restart may repeat a reminder once - an acceptable tradeoff for the
+// simplicity of not serializing the notified set.
+func (s *botStore) markNotified(chatID int64, uid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.stateLocked(chatID)
+	if state.notified == nil {
+		state.notified = make(map[string]bool)
+	}
+	if state.notified[uid] {
+		return false
+	}
+	state.notified[uid] = true
+	return true
+}
+
+// runBot starts the `bot` subcommand: a Telegram bot backed by the same
+// scheduleCache the `serve` subcommand uses, answering schedule queries and
+// DMing subscribers ahead of match start.
+func runBot(args []string) error {
+	fs := flag.NewFlagSet("bot", flag.ExitOnError)
+	token := fs.String("token", "", "Telegram bot token (defaults to the TELEGRAM_BOT_TOKEN env var)")
+	interval := fs.Duration("interval", 30*time.Minute, "how often to re-scrape the schedule")
+	stateDir := fs.String("state-dir", ".", "directory to persist per-chat subscriptions in")
+	var tzs tzFlag
+	fs.Var(&tzs, "tz", "time zone to include in the report, e.g. Europe/Berlin (repeatable, comma-separated; defaults to Asia/Almaty)")
+	primaryTz := fs.String("primary-tz", "", "time zone used as the default sort/display zone; defaults to the first --tz")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		*token = os.Getenv("TELEGRAM_BOT_TOKEN")
+	}
+	if *token == "" {
+		return fmt.Errorf("bot: --token or TELEGRAM_BOT_TOKEN is required")
+	}
+
+	zones, primaryLabel, err := resolveTimeZones(tzs.labels, *primaryTz)
+	if err != nil {
+		return err
+	}
+
+	store, err := loadBotStore(*stateDir)
+	if err != nil {
+		return fmt.Errorf("loading bot state: %w", err)
+	}
+
+	api, err := tgbotapi.NewBotAPI(*token)
+	if err != nil {
+		return fmt.Errorf("connecting to Telegram: %w", err)
+	}
+	slog.Info("bot: authorized", "username", api.Self.UserName)
+
+	cache := &scheduleCache{zones: zones, primaryLabel: primaryLabel}
+	cache.refresh()
+	startRefreshLoop(cache, *interval)
+	startReminderLoop(api, cache, store)
+
+	updates := api.GetUpdatesChan(tgbotapi.NewUpdate(0))
+	for update := range updates {
+		if update.Message == nil || !update.Message.IsCommand() {
+			continue
+		}
+		handleBotMessage(api, cache, store, update.Message)
+	}
+	return nil
+}
+
+func handleBotMessage(api *tgbotapi.BotAPI, cache *scheduleCache, store *botStore, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	args := strings.TrimSpace(msg.CommandArguments())
+	zone := chatZone(cache, store, chatID)
+
+	reply := ""
+	switch msg.Command() {
+	case "today":
+		reply = formatSchedule(filterByDay(cache.currentData(), zone, 0), zone)
+	case "tomorrow":
+		reply = formatSchedule(filterByDay(cache.currentData(), zone, 1), zone)
+	case "next":
+		reply = formatNext(cache.currentData(), zone)
+	case "region":
+		reply = formatSchedule(filterByField(cache.currentData(), args, func(t TypedTranslation) string { return t.Region }), zone)
+	case "tournament":
+		reply = formatSchedule(filterByField(cache.currentData(), args, func(t TypedTranslation) string { return t.Tournament }), zone)
+	case "subscribe":
+		reply = handleSubscribeCommand(store, chatID, args)
+	case "settz":
+		reply = handleSettzCommand(store, chatID, args)
+	default:
+		reply = "Unknown command. Try /today, /tomorrow, /next, /region <name>, /tournament <name>, /subscribe region=EMEA lead=30m, /settz <zone>."
+	}
+
+	if reply == "" {
+		return
+	}
+	if _, err := api.Send(tgbotapi.NewMessage(chatID, reply)); err != nil {
+		slog.Error("bot: failed to reply", "chat_id", chatID, "error", err)
+	}
+}
+
+func (c *scheduleCache) currentData() []TypedTranslation {
+	data, _, _ := c.snapshot()
+	return data
+}
+
+func chatZone(cache *scheduleCache, store *botStore, chatID int64) string {
+	if tz := store.snapshot(chatID).TZ; tz != "" {
+		return tz
+	}
+	return cache.primaryLabel
+}
+
+func handleSubscribeCommand(store *botStore, chatID int64, args string) string {
+	sub, err := parseSubscription(args)
+	if err != nil {
+		return fmt.Sprintf("Couldn't parse subscription: %v. Usage: /subscribe region=EMEA lead=30m", err)
+	}
+	if err := store.addSubscription(chatID, sub); err != nil {
+		return fmt.Sprintf("Failed to save subscription: %v", err)
+	}
+	return "Subscribed. I'll DM you before matches start."
+}
+
+func parseSubscription(args string) (subscription, error) {
+	sub := subscription{Lead: 30 * time.Minute}
+	for _, token := range strings.Fields(args) {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			return subscription{}, fmt.Errorf("invalid argument %q, expected key=value", token)
+		}
+		switch key {
+		case "region":
+			sub.Region = value
+		case "tournament":
+			sub.Tournament = value
+		case "lead":
+			lead, err := time.ParseDuration(value)
+			if err != nil {
+				return subscription{}, fmt.Errorf("invalid lead duration %q: %w", value, err)
+			}
+			sub.Lead = lead
+		default:
+			return subscription{}, fmt.Errorf("unknown subscribe option %q", key)
+		}
+	}
+	return sub, nil
+}
+
+func handleSettzCommand(store *botStore, chatID int64, args string) string {
+	if args == "" {
+		return "Usage: /settz Europe/Berlin"
+	}
+	if _, err := time.LoadLocation(args); err != nil {
+		return fmt.Sprintf("Unknown time zone %q: %v", args, err)
+	}
+	if err := store.setTZ(chatID, args); err != nil {
+		return fmt.Sprintf("Failed to save time zone: %v", err)
+	}
+	return fmt.Sprintf("Time zone set to %s.", args)
+}
+
+func filterByDay(data []TypedTranslation, zoneLabel string, dayOffset int) []TypedTranslation {
+	loc, err := time.LoadLocation(zoneLabel)
+	if err != nil {
+		loc = time.UTC
+	}
+	year, month, day := time.Now().In(loc).AddDate(0, 0, dayOffset).Date()
+
+	res := make([]TypedTranslation, 0)
+	for _, t := range data {
+		y, m, d := t.OriginalTime.In(loc).Date()
+		if y == year && m == month && d == day {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func filterByField(data []TypedTranslation, value string, field func(TypedTranslation) string) []TypedTranslation {
+	res := make([]TypedTranslation, 0)
+	for _, t := range data {
+		if strings.EqualFold(field(t), value) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func filterBySubscription(data []TypedTranslation, sub subscription) []TypedTranslation {
+	res := make([]TypedTranslation, 0)
+	for _, t := range data {
+		if sub.Region != "" && !strings.EqualFold(t.Region, sub.Region) {
+			continue
+		}
+		if sub.Tournament != "" && !strings.EqualFold(t.Tournament, sub.Tournament) {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+// formatNext reports the soonest translation still ahead of now; data is
+// assumed sorted ascending by the cache's primary zone, which preserves
+// chronological order regardless of which zone is requested.
+func formatNext(data []TypedTranslation, zoneLabel string) string {
+	now := time.Now()
+	for _, t := range data {
+		if t.OriginalTime.After(now) {
+			return formatSchedule([]TypedTranslation{t}, zoneLabel)
+		}
+	}
+	return "No upcoming matches."
+}
+
+func formatSchedule(list []TypedTranslation, zoneLabel string) string {
+	if len(list) == 0 {
+		return "No matches."
+	}
+	loc, err := time.LoadLocation(zoneLabel)
+	if err != nil {
+		loc = time.UTC
+	}
+	lines := make([]string, 0, len(list))
+	for _, t := range list {
+		zoneTime := t.OriginalTime.In(loc)
+		lines = append(lines, fmt.Sprintf("%s - %s (%s)", zoneTime.Format("02 Jan 15:04 MST"), t.Tournament, t.Region))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startReminderLoop DMs every subscriber lead before OriginalTime, checking
+// once a minute for translations that just entered their subscription's
+// reminder window.
+func startReminderLoop(api *tgbotapi.BotAPI, cache *scheduleCache, store *botStore) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			checkReminders(api, cache, store)
+		}
+	}()
+}
+
+// dueForReminder reports whether now falls within the one-minute reminder
+// window starting at startTime-lead, matching checkReminders's once-a-minute
+// polling cadence so a match isn't reminded twice or skipped between ticks.
+func dueForReminder(now, startTime time.Time, lead time.Duration) bool {
+	due := startTime.Add(-lead)
+	return !now.Before(due) && now.Sub(due) <= time.Minute
+}
+
+func checkReminders(api *tgbotapi.BotAPI, cache *scheduleCache, store *botStore) {
+	data := cache.currentData()
+	now := time.Now()
+
+	for _, chatID := range store.chatIDs() {
+		state := store.snapshot(chatID)
+		for _, sub := range state.Subscriptions {
+			for _, t := range filterBySubscription(data, sub) {
+				if !dueForReminder(now, t.OriginalTime, sub.Lead) {
+					continue
+				}
+				if !store.markNotified(chatID, translationUID(t)) {
+					continue
+				}
+
+				zone := state.TZ
+				if zone == "" {
+					zone = cache.primaryLabel
+				}
+				text := fmt.Sprintf("Starting in %s: %s", sub.Lead, formatSchedule([]TypedTranslation{t}, zone))
+				if _, err := api.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+					slog.Error("bot: failed to send reminder", "chat_id", chatID, "error", err)
+				}
+			}
+		}
+	}
+}
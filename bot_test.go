@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatScheduleUsesRequestedZoneNotCacheZones(t *testing.T) {
+	// Translation only carries a precomputed Asia/Almaty ZoneTime, but the
+	// chat asked for America/New_York via /settz - formatSchedule must
+	// resolve that zone itself rather than falling back to OriginalTime.
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	if err != nil {
+		t.Fatalf("loading Asia/Almaty: %v", err)
+	}
+	originalTime := time.Date(2026, 7, 25, 10, 0, 0, 0, time.UTC)
+	translation := TypedTranslation{
+		Tournament:   "Pro League",
+		Region:       "EMEA",
+		OriginalTime: originalTime,
+		Times:        []ZoneTime{{Label: "Asia/Almaty", Time: originalTime.In(almaty)}},
+	}
+
+	got := formatSchedule([]TypedTranslation{translation}, "America/New_York")
+
+	if !strings.Contains(got, "06:00 EDT") {
+		t.Errorf("formatSchedule = %q, want it to render 06:00 EDT (America/New_York)", got)
+	}
+	if strings.Contains(got, "UTC") {
+		t.Errorf("formatSchedule = %q, should not fall back to UTC", got)
+	}
+}
+
+func TestFormatScheduleUnknownZoneFallsBackToUTC(t *testing.T) {
+	translation := TypedTranslation{
+		Tournament:   "Pro League",
+		Region:       "EMEA",
+		OriginalTime: time.Date(2026, 7, 25, 10, 0, 0, 0, time.UTC),
+	}
+
+	got := formatSchedule([]TypedTranslation{translation}, "Not/AZone")
+
+	if !strings.Contains(got, "UTC") {
+		t.Errorf("formatSchedule = %q, want fallback to UTC for an unloadable zone", got)
+	}
+}
+
+func TestDueForReminder(t *testing.T) {
+	start := time.Date(2026, 7, 25, 18, 0, 0, 0, time.UTC)
+	lead := 30 * time.Minute
+	due := start.Add(-lead) // 17:30
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"before window", due.Add(-time.Second), false},
+		{"window opens", due, true},
+		{"mid window", due.Add(30 * time.Second), true},
+		{"window closes", due.Add(time.Minute), true},
+		{"after window", due.Add(time.Minute + time.Second), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := dueForReminder(c.now, start, lead); got != c.want {
+				t.Errorf("dueForReminder(%s, %s, %s) = %v, want %v", c.now, start, lead, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+// ScrapeErrorCode classifies what stage of scraping/parsing failed, so
+// callers (and log aggregation) can distinguish a dead upstream from a
+// shape change in its markup.
+type ScrapeErrorCode string
+
+const (
+	ErrFetch           ScrapeErrorCode = "fetch"
+	ErrStatus          ScrapeErrorCode = "status"
+	ErrNextDataMissing ScrapeErrorCode = "next_data_missing"
+	ErrJSONShape       ScrapeErrorCode = "json_shape"
+	ErrHTMLParse       ScrapeErrorCode = "html_parse"
+	ErrRowParse        ScrapeErrorCode = "row_parse"
+	ErrTimeParse       ScrapeErrorCode = "time_parse"
+	ErrIO              ScrapeErrorCode = "io"
+)
+
+const maxSnippetLen = 200
+
+// ScrapeError is returned by every scraping/parsing stage instead of
+// panicking, carrying enough of the offending input to debug a production
+// failure without reproducing it locally.
+type ScrapeError struct {
+	Code    ScrapeErrorCode
+	Path    string // offending field path, e.g. "props.pageProps.blocks"
+	Snippet string // offending raw input, truncated to maxSnippetLen
+	Err     error
+}
+
+func newScrapeError(code ScrapeErrorCode, path, snippet string, err error) *ScrapeError {
+	return &ScrapeError{Code: code, Path: path, Snippet: truncateSnippet(snippet), Err: err}
+}
+
+func (e *ScrapeError) Error() string {
+	msg := string(e.Code)
+	if e.Path != "" {
+		msg += ": " + e.Path
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+func truncateSnippet(s string) string {
+	if len(s) <= maxSnippetLen {
+		return s
+	}
+	return s[:maxSnippetLen] + "..."
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// classToField maps the `class` attribute Blizzard puts on each schedule
+// table's <td> cells to the Translation field it carries.
+var classToField = map[string]string{
+	"dateBody":       "date",
+	"tournamentBody": "tournament",
+	"regionBody":     "region",
+	"timeBody":       "time",
+	"broadcastBody":  "broadcast",
+}
+
+// parseArticleRawHtml extracts one Translation per schedule table row out of
+// the raw HTML Blizzard embeds in the page's rich text editor block.
+// Malformed rows are skipped and logged rather than aborting the whole
+// article, so one bad row doesn't take down the rest of the schedule.
+func parseArticleRawHtml(articleRaw string) ([]Translation, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(articleRaw))
+	if err != nil {
+		return nil, newScrapeError(ErrHTMLParse, "articleRawHtml", articleRaw, err)
+	}
+
+	res := make([]Translation, 0)
+	doc.Find("table tbody tr").Each(func(rowIdx int, row *goquery.Selection) {
+		translation, err := rowToTranslation(rowIdx, row)
+		if err != nil {
+			slog.Warn("skipping malformed schedule row", "error", err)
+			return
+		}
+		res = append(res, translation)
+	})
+
+	return res, nil
+}
+
+func rowToTranslation(rowIdx int, row *goquery.Selection) (Translation, error) {
+	fields := make(map[string]string, len(classToField))
+	row.Find("td").Each(func(_ int, cell *goquery.Selection) {
+		class, ok := cell.Attr("class")
+		if !ok {
+			return
+		}
+		field, ok := classToField[class]
+		if !ok {
+			return
+		}
+		if field == "broadcast" {
+			fields[field] = broadcastCellText(cell)
+		} else {
+			fields[field] = cellText(cell)
+		}
+	})
+
+	for _, field := range []string{"date", "tournament", "region", "time"} {
+		if fields[field] == "" {
+			rowHTML, _ := row.Html()
+			return Translation{}, newScrapeError(ErrRowParse, fmt.Sprintf("row[%d].%s", rowIdx, field), rowHTML, fmt.Errorf("missing required field %q", field))
+		}
+	}
+
+	return Translation{
+		Date:       fields["date"],
+		Tournament: fields["tournament"],
+		Region:     fields["region"],
+		Time:       fields["time"],
+		Broadcast:  fields["broadcast"],
+	}, nil
+}
+
+// cellText returns a cell's text with inner whitespace collapsed.
+func cellText(cell *goquery.Selection) string {
+	return strings.Join(strings.Fields(cell.Text()), " ")
+}
+
+// broadcastCellText is like cellText but unwraps an inner <a> to its href,
+// so Broadcast carries a usable URL when the cell links out rather than the
+// link's display text.
+func broadcastCellText(cell *goquery.Selection) string {
+	if href, ok := cell.Find("a").Attr("href"); ok && strings.TrimSpace(href) != "" {
+		return strings.TrimSpace(href)
+	}
+	return cellText(cell)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func mustParseRow(t *testing.T, rowHTML string) *goquery.Selection {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table><tbody>" + rowHTML + "</tbody></table>"))
+	if err != nil {
+		t.Fatalf("parsing test row: %v", err)
+	}
+	return doc.Find("tr").First()
+}
+
+func TestRowToTranslation(t *testing.T) {
+	row := mustParseRow(t, `<tr>
+		<td class="dateBody">07-25-2026</td>
+		<td class="tournamentBody">Pro League</td>
+		<td class="regionBody">EMEA</td>
+		<td class="timeBody">3:00 PM PT</td>
+		<td class="broadcastBody"><a href="https://example.com/watch">watch</a></td>
+	</tr>`)
+
+	translation, err := rowToTranslation(0, row)
+	if err != nil {
+		t.Fatalf("rowToTranslation returned error: %v", err)
+	}
+
+	want := Translation{
+		Date:       "07-25-2026",
+		Tournament: "Pro League",
+		Region:     "EMEA",
+		Time:       "3:00 PM PT",
+		Broadcast:  "https://example.com/watch",
+	}
+	if translation != want {
+		t.Errorf("rowToTranslation = %+v, want %+v", translation, want)
+	}
+}
+
+func TestRowToTranslationOnlyUnwrapsHrefForBroadcast(t *testing.T) {
+	row := mustParseRow(t, `<tr>
+		<td class="dateBody">07-25-2026</td>
+		<td class="tournamentBody"><a href="https://example.com/tournament">Pro League</a></td>
+		<td class="regionBody">EMEA</td>
+		<td class="timeBody">3:00 PM PT</td>
+		<td class="broadcastBody"><a href="https://example.com/watch">watch</a></td>
+	</tr>`)
+
+	translation, err := rowToTranslation(0, row)
+	if err != nil {
+		t.Fatalf("rowToTranslation returned error: %v", err)
+	}
+
+	if translation.Tournament != "Pro League" {
+		t.Errorf("Tournament = %q, want the link text %q, not its href", translation.Tournament, "Pro League")
+	}
+	if translation.Broadcast != "https://example.com/watch" {
+		t.Errorf("Broadcast = %q, want the href %q", translation.Broadcast, "https://example.com/watch")
+	}
+}
+
+func TestRowToTranslationMissingField(t *testing.T) {
+	row := mustParseRow(t, `<tr>
+		<td class="dateBody">07-25-2026</td>
+		<td class="tournamentBody">Pro League</td>
+		<td class="timeBody">3:00 PM PT</td>
+	</tr>`)
+
+	if _, err := rowToTranslation(0, row); err == nil {
+		t.Fatal("rowToTranslation with missing region: expected error, got nil")
+	}
+}
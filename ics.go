@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateTimeFormat = "20060102T150405Z"
+	icsFoldWidth      = 75
+	icsLineBreak      = "\r\n"
+)
+
+// vTimeZoneLA is a static VTIMEZONE block describing America/Los_Angeles
+// DST transitions, the zone Blizzard's "PT" schedule times are given in.
+// No event property references it by TZID: DTSTART and DTEND are always
+// written in UTC.
+const vTimeZoneLA = "BEGIN:VTIMEZONE\r\n" +
+	"TZID:America/Los_Angeles\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"TZOFFSETFROM:-0800\r\n" +
+	"TZOFFSETTO:-0700\r\n" +
+	"TZNAME:PDT\r\n" +
+	"DTSTART:19700308T020000\r\n" +
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"TZOFFSETFROM:-0700\r\n" +
+	"TZOFFSETTO:-0800\r\n" +
+	"TZNAME:PST\r\n" +
+	"DTSTART:19701101T020000\r\n" +
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n"
+
+func reportICS(res []TypedTranslation, duration time.Duration) error {
+	if err := os.WriteFile("overwatch-translations.ics", buildICSReport(res, duration), 0777); err != nil {
+		return newScrapeError(ErrIO, "overwatch-translations.ics", "", err)
+	}
+	return nil
+}
+
+func buildICSReport(res []TypedTranslation, duration time.Duration) []byte {
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VCALENDAR" + icsLineBreak)
+	sb.WriteString("VERSION:2.0" + icsLineBreak)
+	sb.WriteString(foldICSLine("PRODID:-//overwatch-league-schedule-parser//EN") + icsLineBreak)
+	sb.WriteString(foldICSLine("X-WR-CALNAME:Overwatch League Schedule") + icsLineBreak)
+	sb.WriteString(vTimeZoneLA)
+
+	for _, translation := range res {
+		sb.WriteString(translationToVEvent(translation, duration))
+	}
+
+	sb.WriteString("END:VCALENDAR" + icsLineBreak)
+
+	return []byte(sb.String())
+}
+
+func translationToVEvent(t TypedTranslation, duration time.Duration) string {
+	start := t.OriginalTime.UTC()
+	end := start.Add(duration)
+
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VEVENT" + icsLineBreak)
+	sb.WriteString(foldICSLine("UID:"+translationUID(t)) + icsLineBreak)
+	sb.WriteString("DTSTAMP:" + start.Format(icsDateTimeFormat) + icsLineBreak)
+	sb.WriteString("DTSTART:" + start.Format(icsDateTimeFormat) + icsLineBreak)
+	sb.WriteString("DTEND:" + end.Format(icsDateTimeFormat) + icsLineBreak)
+	sb.WriteString(foldICSLine("SUMMARY:"+escapeICSText(fmt.Sprintf("%s - %s", t.Tournament, t.Region))) + icsLineBreak)
+	if description := translationDescription(t); description != "" {
+		sb.WriteString(foldICSLine("DESCRIPTION:"+escapeICSText(description)) + icsLineBreak)
+	}
+	sb.WriteString("END:VEVENT" + icsLineBreak)
+	return sb.String()
+}
+
+func translationDescription(t TypedTranslation) string {
+	lines := make([]string, 0, len(t.Times)+1)
+
+	if t.Broadcast != "" {
+		if strings.HasPrefix(t.Broadcast, "http://") || strings.HasPrefix(t.Broadcast, "https://") {
+			lines = append(lines, "Broadcast: "+t.Broadcast)
+		} else {
+			lines = append(lines, t.Broadcast)
+		}
+	}
+
+	for _, zoneTime := range t.Times {
+		lines = append(lines, fmt.Sprintf("%s: %s", zoneDisplayName(zoneTime.Label), zoneTime.Time.Format("02 Jan 06 15:04 MST")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// translationUID hashes the fields that identify a single broadcast slot so
+// re-running the parser and re-importing the calendar updates existing
+// events instead of duplicating them.
+func translationUID(t TypedTranslation) string {
+	sum := sha1.Sum([]byte(t.Tournament + "|" + t.Region + "|" + t.OriginalTime.UTC().Format(time.RFC3339)))
+	return hex.EncodeToString(sum[:]) + "@overwatch-league-schedule-parser"
+}
+
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		",", "\\,",
+		";", "\\;",
+		"\n", "\\n",
+	)
+	return replacer.Replace(s)
+}
+
+// foldICSLine wraps a logical ICS line at icsFoldWidth octets as required by
+// RFC 5545, continuing it with a single leading space.
+func foldICSLine(line string) string {
+	if len(line) <= icsFoldWidth {
+		return line
+	}
+
+	var sb strings.Builder
+	for len(line) > 0 {
+		width := icsFoldWidth
+		if sb.Len() > 0 {
+			width--
+		}
+		if width > len(line) {
+			width = len(line)
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(icsLineBreak + " ")
+		}
+		sb.WriteString(line[:width])
+		line = line[width:]
+	}
+	return sb.String()
+}
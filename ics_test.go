@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildICSReportContainsEventForEachTranslation(t *testing.T) {
+	start := time.Date(2026, 7, 25, 18, 0, 0, 0, time.UTC)
+	res := []TypedTranslation{
+		{Tournament: "Pro League", Region: "EMEA", OriginalTime: start},
+		{Tournament: "Contenders", Region: "NA", OriginalTime: start.Add(24 * time.Hour)},
+	}
+
+	ics := string(buildICSReport(res, 2*time.Hour))
+
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") {
+		t.Errorf("report does not start with BEGIN:VCALENDAR: %q", ics[:40])
+	}
+	if !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("report does not end with END:VCALENDAR")
+	}
+	if got := strings.Count(ics, "BEGIN:VEVENT"); got != len(res) {
+		t.Errorf("got %d VEVENT blocks, want %d", got, len(res))
+	}
+	if !strings.Contains(ics, "SUMMARY:Pro League - EMEA") {
+		t.Errorf("report missing expected SUMMARY line:\n%s", ics)
+	}
+	if !strings.Contains(ics, "DTSTART:20260725T180000Z") {
+		t.Errorf("report missing expected DTSTART line:\n%s", ics)
+	}
+}
+
+func TestFoldICSLineWrapsAtWidth(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("a", 100)
+	folded := foldICSLine(long)
+
+	for _, line := range strings.Split(folded, icsLineBreak) {
+		if len(line) > icsFoldWidth {
+			t.Errorf("line exceeds %d octets: %q (%d)", icsFoldWidth, line, len(line))
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.ReplaceAll(folded, icsLineBreak, ""), " ", "")
+	if unfolded != strings.ReplaceAll(long, " ", "") {
+		// folding only inserts a leading space after the break, so the only
+		// spaces introduced are the ones stripped above.
+		t.Errorf("folding changed the content: got %q, want %q", unfolded, long)
+	}
+}
+
+func TestFoldICSLineLeavesShortLinesAlone(t *testing.T) {
+	short := "SUMMARY:short"
+	if got := foldICSLine(short); got != short {
+		t.Errorf("foldICSLine(%q) = %q, want unchanged", short, got)
+	}
+}
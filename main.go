@@ -2,9 +2,10 @@ package main
 
 import (
 	"encoding/json"
-	"encoding/xml"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"sort"
@@ -17,139 +18,249 @@ const (
 	suffix = "</script>"
 )
 
-var (
-	laLocation     *time.Location
-	almatyLocation *time.Location
-)
+// laLocation is used to interpret the "PT" suffix Blizzard puts on some
+// schedule rows; it is independent of which zones the report is rendered in.
+var laLocation *time.Location
 
-func init() {
+func main() {
 	var err error
 	laLocation, err = time.LoadLocation("America/Los_Angeles")
 	if err != nil {
-		panic(err)
+		slog.Error("loading America/Los_Angeles location failed", "error", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			slog.Error("serve failed", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
-	almatyLocation, err = time.LoadLocation("Asia/Almaty")
+	if len(os.Args) > 1 && os.Args[1] == "bot" {
+		if err := runBot(os.Args[2:]); err != nil {
+			slog.Error("bot failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	duration := flag.Duration("duration", 2*time.Hour, "event duration to use for the ICS export")
+	var tzs tzFlag
+	flag.Var(&tzs, "tz", "time zone to include in the report, e.g. Europe/Berlin (repeatable, comma-separated; defaults to Asia/Almaty)")
+	primaryTz := flag.String("primary-tz", "", "time zone used as the sort key; defaults to the first --tz")
+	flag.Parse()
+
+	zones, primaryLabel, err := resolveTimeZones(tzs.labels, *primaryTz)
 	if err != nil {
-		panic(err)
+		slog.Error("invalid time zone configuration", "error", err)
+		os.Exit(1)
 	}
-}
 
-func main() {
-	bodyString := getPageAsString()
+	bodyString, err := getPageAsString()
+	if err != nil {
+		slog.Error("fetching schedule page failed", "error", err)
+		os.Exit(1)
+	}
 
-	translations := parsePage(bodyString)
+	translations, err := parsePage(bodyString)
+	if err != nil {
+		slog.Error("parsing schedule page failed", "error", err)
+		os.Exit(1)
+	}
 
+	res := fetchAndSort(translations, zones, primaryLabel)
+
+	if err := reportJson(res); err != nil {
+		slog.Error("writing JSON report failed", "error", err)
+		os.Exit(1)
+	}
+	if err := reportCsv(res, zones); err != nil {
+		slog.Error("writing CSV report failed", "error", err)
+		os.Exit(1)
+	}
+	if err := reportICS(res, *duration); err != nil {
+		slog.Error("writing ICS report failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// fetchAndSort converts raw translations into the typed, zone-aware model
+// and sorts it by the primary zone, shared by the one-shot CLI path and the
+// server's periodic refresh. Translations with an unparsable time are
+// skipped and logged rather than aborting the whole report.
+func fetchAndSort(translations []Translation, zones []timeZoneConfig, primaryLabel string) []TypedTranslation {
 	res := make([]TypedTranslation, 0, len(translations))
 	for _, tr := range translations {
-		res = append(res, tr.ToTypedTranslation())
+		typed, err := tr.ToTypedTranslation(zones)
+		if err != nil {
+			slog.Warn("skipping translation with unparsable time", "error", err, "tournament", tr.Tournament, "region", tr.Region)
+			continue
+		}
+		res = append(res, typed)
 	}
 
 	sort.SliceStable(res, func(i, j int) bool {
-		return res[i].AlmatyTime.Before(res[j].AlmatyTime)
+		ti, _ := res[i].TimeFor(primaryLabel)
+		tj, _ := res[j].TimeFor(primaryLabel)
+		return ti.Before(tj)
 	})
 
-	reportJson(res)
-	reportCsv(res)
+	return res
 }
 
-func parsePage(bodyString string) []Translation {
+func parsePage(bodyString string) ([]Translation, error) {
 	prefixIdx := strings.Index(bodyString, prefix)
+	if prefixIdx == -1 {
+		return nil, newScrapeError(ErrNextDataMissing, "", bodyString, fmt.Errorf("%q not found in page", prefix))
+	}
 	bodyString = bodyString[prefixIdx+len(prefix):]
+
 	suffixIdx := strings.Index(bodyString, suffix)
+	if suffixIdx == -1 {
+		return nil, newScrapeError(ErrNextDataMissing, "", bodyString, fmt.Errorf("%q not found after __NEXT_DATA__", suffix))
+	}
 	jsonString := bodyString[:suffixIdx]
 
 	var jsonMap map[string]any
 	if err := json.Unmarshal([]byte(jsonString), &jsonMap); err != nil {
-		panic(err)
+		return nil, newScrapeError(ErrJSONShape, "__NEXT_DATA__", jsonString, err)
+	}
+
+	pageProps, err := getMap(jsonMap, "props", "pageProps")
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := getSlice(pageProps, "blocks")
+	if err != nil {
+		return nil, err
 	}
 
-	blocks := getSlice(getMap(jsonMap, "props", "pageProps"), "blocks")
 	var tabsSlice []any
 	for _, block := range blocks {
-		if tabs, ok := block.(map[string]any)["tabs"]; ok {
-			tabsSlice = getSlice(tabs.(map[string]any), "tabs")
-			break
+		blockMap, ok := block.(map[string]any)
+		if !ok {
+			continue
+		}
+		tabs, ok := blockMap["tabs"]
+		if !ok {
+			continue
 		}
+		tabsMap, ok := tabs.(map[string]any)
+		if !ok {
+			continue
+		}
+		tabsSlice, err = getSlice(tabsMap, "tabs")
+		if err != nil {
+			return nil, err
+		}
+		break
 	}
 
 	translations := make([]Translation, 0)
 
-	for _, tab := range tabsSlice {
-		blocks := tab.(map[string]any)["blocks"].([]any)
-		for _, block := range blocks {
-			articleRaw := getString(block.(map[string]any), "richTextEditor", "articleRawHtml")
-			translations = append(translations, parseArticleRawHtml(articleRaw)...)
+	for tabIdx, tab := range tabsSlice {
+		tabMap, ok := tab.(map[string]any)
+		if !ok {
+			return nil, newScrapeError(ErrJSONShape, fmt.Sprintf("blocks.tabs[%d]", tabIdx), fmt.Sprintf("%v", tab), fmt.Errorf("expected object"))
+		}
+		blockList, ok := tabMap["blocks"].([]any)
+		if !ok {
+			return nil, newScrapeError(ErrJSONShape, fmt.Sprintf("blocks.tabs[%d].blocks", tabIdx), fmt.Sprintf("%v", tabMap["blocks"]), fmt.Errorf("expected array"))
+		}
+		for blockIdx, block := range blockList {
+			blockMap, ok := block.(map[string]any)
+			if !ok {
+				return nil, newScrapeError(ErrJSONShape, fmt.Sprintf("blocks.tabs[%d].blocks[%d]", tabIdx, blockIdx), fmt.Sprintf("%v", block), fmt.Errorf("expected object"))
+			}
+			articleRaw, err := getString(blockMap, "richTextEditor", "articleRawHtml")
+			if err != nil {
+				return nil, err
+			}
+			parsed, err := parseArticleRawHtml(articleRaw)
+			if err != nil {
+				return nil, err
+			}
+			translations = append(translations, parsed...)
 		}
 	}
 
-	return translations
+	return translations, nil
 }
 
-func getPageAsString() string {
+func getPageAsString() (string, error) {
 	resp, err := http.DefaultClient.Get("https://overwatchleague.com/en-us/pathtopro/schedule")
 	if err != nil {
-		panic(err)
+		return "", newScrapeError(ErrFetch, "", "", err)
 	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != 200 {
-		panic("response status: " + fmt.Sprintf("%d", resp.StatusCode))
+		return "", newScrapeError(ErrStatus, fmt.Sprintf("status=%d", resp.StatusCode), "", nil)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return "", newScrapeError(ErrIO, "", "", err)
 	}
-	return string(bodyBytes)
+	return string(bodyBytes), nil
 }
 
-func parseArticleRawHtml(articleRaw string) []Translation {
-	articleRaw = strings.ReplaceAll(articleRaw, "&", "amp;")
-	var htmlTable HtmlTable
-	err := xml.Unmarshal([]byte(articleRaw), &htmlTable)
+func reportJson(res []TypedTranslation) error {
+	jsonBytes, err := buildJSONReport(res)
 	if err != nil {
-		panic(err)
+		return err
 	}
-
-	res := make([]Translation, 0, len(htmlTable.TBody.Tr))
-	for _, tr := range htmlTable.TBody.Tr {
-		res = append(res, tr.ToTranslation())
+	if err := os.WriteFile("overwatch-translations.json", jsonBytes, 0777); err != nil {
+		return newScrapeError(ErrIO, "overwatch-translations.json", "", err)
 	}
-	return res
+	return nil
 }
 
-func reportJson(res []TypedTranslation) {
-	jsonBytes, err := json.Marshal(res)
-	if err != nil {
-		panic(err)
-	}
-	if err = os.WriteFile("overwatch-translations.json", jsonBytes, 0777); err != nil {
-		panic(err)
+func buildJSONReport(res []TypedTranslation) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+func reportCsv(res []TypedTranslation, zones []timeZoneConfig) error {
+	if err := os.WriteFile("overwatch-translations.csv", buildCSVReport(res, zones), 0777); err != nil {
+		return newScrapeError(ErrIO, "overwatch-translations.csv", "", err)
 	}
+	return nil
 }
 
-func reportCsv(res []TypedTranslation) {
-	csv := "Almaty Time,Tournament,Region,Broadcast,Original Time,Original Date\r\n"
+func buildCSVReport(res []TypedTranslation, zones []timeZoneConfig) []byte {
+	headers := make([]string, 0, len(zones)+4)
+	for _, zone := range zones {
+		headers = append(headers, zoneDisplayName(zone.Label)+" Time")
+	}
+	headers = append(headers, "Tournament", "Region", "Broadcast", "Original Time", "Original Date")
+
+	csv := strings.Join(headers, ",") + "\r\n"
 	for _, translation := range res {
-		csv += fmt.Sprintf("%v,%s,%s,%s,%v,%s\r\n",
-			translation.AlmatyTime.Format("02 Jan 06 15:04 MST"),
+		columns := make([]string, 0, len(zones)+4)
+		for _, zone := range zones {
+			zoneTime, _ := translation.TimeFor(zone.Label)
+			columns = append(columns, zoneTime.Format("02 Jan 06 15:04 MST"))
+		}
+		columns = append(columns,
 			translation.Tournament,
 			translation.Region,
 			translation.Broadcast,
 			translation.OriginalTime.Format("02 Jan 06 15:04 MST"),
 			translation.OriginalDate,
 		)
+		csv += strings.Join(columns, ",") + "\r\n"
 	}
-	if err := os.WriteFile("overwatch-translations.csv", []byte(csv), 0777); err != nil {
-		panic(err)
-	}
+	return []byte(csv)
 }
 
 type TypedTranslation struct {
-	AlmatyTime   time.Time `json:"almatyTime"`
-	Tournament   string    `json:"tournament"`
-	Region       string    `json:"region"`
-	Broadcast    string    `json:"broadcast"`
-	OriginalTime time.Time `json:"originalTime"`
-	OriginalDate string    `json:"originalDate"`
+	Times        []ZoneTime `json:"times"`
+	Tournament   string     `json:"tournament"`
+	Region       string     `json:"region"`
+	Broadcast    string     `json:"broadcast"`
+	OriginalTime time.Time  `json:"originalTime"`
+	OriginalDate string     `json:"originalDate"`
 }
 
 type Translation struct {
@@ -160,82 +271,80 @@ type Translation struct {
 	Broadcast  string
 }
 
-func (t Translation) ToTypedTranslation() TypedTranslation {
+func (t Translation) ToTypedTranslation(zones []timeZoneConfig) (TypedTranslation, error) {
 	var timeVal time.Time
 	var err error
 	if strings.HasSuffix(t.Time, "PT") {
 		timeVal, err = time.ParseInLocation("01-02-2006 3:04 PM", t.Date+" "+t.Time[:len(t.Time)-3], laLocation)
-		if err != nil {
-			panic(err)
-		}
 	} else {
 		timeVal, err = time.Parse("01-02-2006 3:04 PM MST", t.Date+" "+t.Time)
-		if err != nil {
-			panic(err)
-		}
 	}
+	if err != nil {
+		return TypedTranslation{}, newScrapeError(ErrTimeParse, t.Date+" "+t.Time, "", err)
+	}
+
+	times := make([]ZoneTime, 0, len(zones))
+	for _, zone := range zones {
+		times = append(times, ZoneTime{Label: zone.Label, Time: timeVal.In(zone.Location)})
+	}
+
 	return TypedTranslation{
 		OriginalDate: t.Date + " " + t.Time,
 		Tournament:   t.Tournament,
 		Region:       t.Region,
-		AlmatyTime:   timeVal.In(almatyLocation),
+		Times:        times,
 		OriginalTime: timeVal,
 		Broadcast:    t.Broadcast,
-	}
-}
-
-type HtmlTable struct {
-	XMLName xml.Name `xml:"table"`
-	THead   any      `xml:"thead"`
-	TBody   TBody    `xml:"tbody"`
-}
-
-type TBody struct {
-	Tr []Tr `xml:"tr"`
-}
-
-type Tr struct {
-	Td []Td `xml:"td"`
+	}, nil
 }
 
-func (tr Tr) GetField(name string) string {
-	for _, td := range tr.Td {
-		if td.Key == name {
-			return td.Value
+// getMap walks path through nested JSON objects, returning a ScrapeError
+// instead of panicking if a key is missing or not itself an object.
+func getMap(jsonMap map[string]any, path ...string) (map[string]any, error) {
+	cur := jsonMap
+	for i, key := range path {
+		val, ok := cur[key]
+		if !ok {
+			return nil, newScrapeError(ErrJSONShape, strings.Join(path[:i+1], "."), "", fmt.Errorf("missing key %q", key))
 		}
+		m, ok := val.(map[string]any)
+		if !ok {
+			return nil, newScrapeError(ErrJSONShape, strings.Join(path[:i+1], "."), fmt.Sprintf("%v", val), fmt.Errorf("expected object at %q", key))
+		}
+		cur = m
 	}
-	return ""
+	return cur, nil
 }
 
-func (tr Tr) ToTranslation() Translation {
-	return Translation{
-		Date:       tr.GetField("dateBody"),
-		Tournament: tr.GetField("tournamentBody"),
-		Region:     tr.GetField("regionBody"),
-		Time:       tr.GetField("timeBody"),
-		Broadcast:  tr.GetField("broadcastBody"),
+// getString walks path through nested JSON objects and returns the string
+// found at the final key.
+func getString(jsonMap map[string]any, path ...string) (string, error) {
+	parent, err := getMap(jsonMap, path[:len(path)-1]...)
+	if err != nil {
+		return "", err
 	}
-}
-
-type Td struct {
-	Key   string `xml:"class,attr"`
-	Value string `xml:",chardata"`
-}
 
-func getMap(jsonMap map[string]any, path ...string) map[string]any {
-	if len(path) == 1 {
-		return jsonMap[path[0]].(map[string]any)
+	lastKey := path[len(path)-1]
+	val, ok := parent[lastKey]
+	if !ok {
+		return "", newScrapeError(ErrJSONShape, strings.Join(path, "."), "", fmt.Errorf("missing key %q", lastKey))
 	}
-	return getMap(jsonMap[path[0]].(map[string]any), path[1:]...)
-}
-
-func getString(jsonMap map[string]any, path ...string) string {
-	if len(path) == 1 {
-		return jsonMap[path[0]].(string)
+	s, ok := val.(string)
+	if !ok {
+		return "", newScrapeError(ErrJSONShape, strings.Join(path, "."), fmt.Sprintf("%v", val), fmt.Errorf("expected string at %q", lastKey))
 	}
-	return getString(jsonMap[path[0]].(map[string]any), path[1:]...)
+	return s, nil
 }
 
-func getSlice(jsonMap map[string]any, path string) []any {
-	return jsonMap[path].([]any)
+// getSlice returns the array found at jsonMap[path].
+func getSlice(jsonMap map[string]any, path string) ([]any, error) {
+	val, ok := jsonMap[path]
+	if !ok {
+		return nil, newScrapeError(ErrJSONShape, path, "", fmt.Errorf("missing key %q", path))
+	}
+	s, ok := val.([]any)
+	if !ok {
+		return nil, newScrapeError(ErrJSONShape, path, fmt.Sprintf("%v", val), fmt.Errorf("expected array at %q", path))
+	}
+	return s, nil
 }
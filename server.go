@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// scheduleCache holds the most recently scraped schedule along with the
+// report configuration it was built with, refreshed on a timer by runServe
+// and read by the HTTP handlers under a RWMutex.
+type scheduleCache struct {
+	zones        []timeZoneConfig
+	primaryLabel string
+	duration     time.Duration
+
+	mu          sync.RWMutex
+	data        []TypedTranslation
+	lastFetched time.Time
+	lastError   error
+}
+
+func (c *scheduleCache) refresh() {
+	bodyString, err := getPageAsString()
+	if err != nil {
+		c.recordError(err)
+		return
+	}
+
+	translations, err := parsePage(bodyString)
+	if err != nil {
+		c.recordError(err)
+		return
+	}
+
+	res := fetchAndSort(translations, c.zones, c.primaryLabel)
+
+	c.mu.Lock()
+	c.data = res
+	c.lastFetched = time.Now()
+	c.lastError = nil
+	c.mu.Unlock()
+}
+
+func (c *scheduleCache) recordError(err error) {
+	slog.Error("schedule refresh failed", "error", err)
+	c.mu.Lock()
+	c.lastError = err
+	c.mu.Unlock()
+}
+
+func (c *scheduleCache) snapshot() (data []TypedTranslation, lastFetched time.Time, lastError error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.data, c.lastFetched, c.lastError
+}
+
+// startRefreshLoop keeps a scheduleCache warm on interval for the lifetime of
+// the process, shared by the `serve` and `bot` subcommands.
+func startRefreshLoop(cache *scheduleCache, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			cache.refresh()
+		}
+	}()
+}
+
+// runServe starts the `serve` subcommand: it scrapes once up front, then
+// keeps the cache warm on --interval and exposes it over HTTP until the
+// process is killed.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := fs.String("listen", ":8080", "address to listen on")
+	interval := fs.Duration("interval", 30*time.Minute, "how often to re-scrape the schedule")
+	duration := fs.Duration("duration", 2*time.Hour, "event duration to use for the ICS export")
+	var tzs tzFlag
+	fs.Var(&tzs, "tz", "time zone to include in the report, e.g. Europe/Berlin (repeatable, comma-separated; defaults to Asia/Almaty)")
+	primaryTz := fs.String("primary-tz", "", "time zone used as the sort key; defaults to the first --tz")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	zones, primaryLabel, err := resolveTimeZones(tzs.labels, *primaryTz)
+	if err != nil {
+		return err
+	}
+
+	cache := &scheduleCache{zones: zones, primaryLabel: primaryLabel, duration: *duration}
+	cache.refresh()
+	startRefreshLoop(cache, *interval)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/schedule.json", cache.handleJSON)
+	mux.HandleFunc("/schedule.csv", cache.handleCSV)
+	mux.HandleFunc("/schedule.ics", cache.handleICS)
+	mux.HandleFunc("/healthz", cache.handleHealthz)
+
+	slog.Info("serving cached schedule", "listen", *listen, "interval", interval.String())
+	return http.ListenAndServe(*listen, mux)
+}
+
+func (c *scheduleCache) handleJSON(w http.ResponseWriter, r *http.Request) {
+	data, lastFetched, _ := c.snapshot()
+	payload, err := buildJSONReport(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeCachedResponse(w, r, payload, "application/json", lastFetched)
+}
+
+func (c *scheduleCache) handleCSV(w http.ResponseWriter, r *http.Request) {
+	data, lastFetched, _ := c.snapshot()
+	writeCachedResponse(w, r, buildCSVReport(data, c.zones), "text/csv; charset=utf-8", lastFetched)
+}
+
+func (c *scheduleCache) handleICS(w http.ResponseWriter, r *http.Request) {
+	data, lastFetched, _ := c.snapshot()
+	writeCachedResponse(w, r, buildICSReport(data, c.duration), "text/calendar; charset=utf-8", lastFetched)
+}
+
+func (c *scheduleCache) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	_, lastFetched, lastErr := c.snapshot()
+
+	status := struct {
+		LastFetched time.Time `json:"lastFetched"`
+		LastError   string    `json:"lastError,omitempty"`
+	}{LastFetched: lastFetched}
+
+	w.Header().Set("Content-Type", "application/json")
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+// writeCachedResponse sets ETag/Last-Modified on payload and honors
+// conditional GETs (If-None-Match / If-Modified-Since) with a 304 so
+// clients like calendar subscribers don't re-download an unchanged schedule.
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, payload []byte, contentType string, lastModified time.Time) {
+	sum := sha1.Sum(payload)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		// The header only carries whole seconds, so compare at that precision.
+		if t, err := time.Parse(http.TimeFormat, since); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Write(payload)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteCachedResponseServesFullBodyByDefault(t *testing.T) {
+	payload := []byte("hello")
+	lastModified := time.Date(2026, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/schedule.json", nil)
+	writeCachedResponse(w, r, payload, "application/json", lastModified)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != string(payload) {
+		t.Errorf("body = %q, want %q", w.Body.String(), payload)
+	}
+}
+
+func TestWriteCachedResponseIfNoneMatchReturns304(t *testing.T) {
+	payload := []byte("hello")
+	sum := sha1.Sum(payload)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	lastModified := time.Date(2026, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/schedule.json", nil)
+	r.Header.Set("If-None-Match", etag)
+	writeCachedResponse(w, r, payload, "application/json", lastModified)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty on 304", w.Body.String())
+	}
+}
+
+func TestWriteCachedResponseIfNoneMatchMismatchServesBody(t *testing.T) {
+	payload := []byte("hello")
+	lastModified := time.Date(2026, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/schedule.json", nil)
+	r.Header.Set("If-None-Match", `"stale-etag"`)
+	writeCachedResponse(w, r, payload, "application/json", lastModified)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestWriteCachedResponseIfModifiedSinceNotAfterReturns304(t *testing.T) {
+	payload := []byte("hello")
+	lastModified := time.Date(2026, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/schedule.json", nil)
+	r.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	writeCachedResponse(w, r, payload, "application/json", lastModified)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+}
+
+func TestWriteCachedResponseIfModifiedSinceStaleServesBody(t *testing.T) {
+	payload := []byte("hello")
+	lastModified := time.Date(2026, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/schedule.json", nil)
+	r.Header.Set("If-Modified-Since", lastModified.Add(-time.Hour).Format(http.TimeFormat))
+	writeCachedResponse(w, r, payload, "application/json", lastModified)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != string(payload) {
+		t.Errorf("body = %q, want %q", w.Body.String(), payload)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// ZoneTime is a single time zone's rendering of a translation's original
+// broadcast time, keyed by the IANA location name it was configured with.
+type ZoneTime struct {
+	Label string    `json:"label"`
+	Time  time.Time `json:"time"`
+}
+
+// timeZoneConfig pairs the IANA label the user configured with the
+// *time.Location resolved from it, so it only needs loading once.
+type timeZoneConfig struct {
+	Label    string
+	Location *time.Location
+}
+
+// defaultTimeZones preserves the tool's original behaviour of reporting a
+// single Almaty column when no --tz flags are given.
+var defaultTimeZones = []string{"Asia/Almaty"}
+
+// tzFlag collects the repeatable, comma-separated --tz flag into an ordered
+// list of zone labels.
+type tzFlag struct {
+	labels []string
+}
+
+func (f *tzFlag) String() string {
+	return strings.Join(f.labels, ",")
+}
+
+func (f *tzFlag) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			f.labels = append(f.labels, part)
+		}
+	}
+	return nil
+}
+
+// resolveTimeZones loads the configured zone labels (or defaultTimeZones if
+// none were given) and picks the primary label used as the sort key,
+// defaulting to the first configured zone. If primary names a zone that
+// isn't already in labels, it's loaded and appended so it's always among
+// the zones a TypedTranslation carries times for.
+func resolveTimeZones(labels []string, primary string) ([]timeZoneConfig, string, error) {
+	if len(labels) == 0 {
+		labels = defaultTimeZones
+	}
+	if primary != "" && !containsLabel(labels, primary) {
+		labels = append(labels, primary)
+	}
+
+	zones := make([]timeZoneConfig, 0, len(labels))
+	for _, label := range labels {
+		loc, err := time.LoadLocation(label)
+		if err != nil {
+			return nil, "", err
+		}
+		zones = append(zones, timeZoneConfig{Label: label, Location: loc})
+	}
+
+	if primary == "" {
+		primary = zones[0].Label
+	}
+
+	return zones, primary, nil
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeFor returns the zone time matching label, if the translation was built
+// with that zone configured.
+func (t TypedTranslation) TimeFor(label string) (time.Time, bool) {
+	for _, zt := range t.Times {
+		if zt.Label == label {
+			return zt.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// zoneDisplayName turns an IANA label like "America/Los_Angeles" into a
+// short, human-friendly header such as "Los Angeles".
+func zoneDisplayName(label string) string {
+	name := label
+	if idx := strings.LastIndex(label, "/"); idx >= 0 {
+		name = label[idx+1:]
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestResolveTimeZonesDefaultsToFirstZone(t *testing.T) {
+	zones, primary, err := resolveTimeZones([]string{"Asia/Almaty", "America/New_York"}, "")
+	if err != nil {
+		t.Fatalf("resolveTimeZones returned error: %v", err)
+	}
+	if primary != "Asia/Almaty" {
+		t.Errorf("primary = %q, want %q", primary, "Asia/Almaty")
+	}
+	if len(zones) != 2 {
+		t.Errorf("len(zones) = %d, want 2", len(zones))
+	}
+}
+
+func TestResolveTimeZonesFoldsPrimaryIntoZones(t *testing.T) {
+	zones, primary, err := resolveTimeZones([]string{"Asia/Almaty", "America/New_York"}, "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("resolveTimeZones returned error: %v", err)
+	}
+	if primary != "Europe/Berlin" {
+		t.Errorf("primary = %q, want %q", primary, "Europe/Berlin")
+	}
+
+	found := false
+	for _, z := range zones {
+		if z.Label == primary {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("zones %v does not include primary label %q", zones, primary)
+	}
+}
+
+func TestResolveTimeZonesPrimaryAlreadyInList(t *testing.T) {
+	zones, primary, err := resolveTimeZones([]string{"Asia/Almaty", "America/New_York"}, "America/New_York")
+	if err != nil {
+		t.Fatalf("resolveTimeZones returned error: %v", err)
+	}
+	if primary != "America/New_York" {
+		t.Errorf("primary = %q, want %q", primary, "America/New_York")
+	}
+	if len(zones) != 2 {
+		t.Errorf("len(zones) = %d, want 2 (primary should not be duplicated)", len(zones))
+	}
+}